@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/scott-linder/blackfriday-tool/internal/backend"
+)
+
+// render runs b.Render over input cfg.Repeat times, as in the single-file
+// path, returning the final output.
+func render(input []byte, b backend.Backend, opts backend.Options) ([]byte, error) {
+	var output []byte
+	var err error
+	for i := 0; i < cfg.Repeat; i++ {
+		if output, err = b.Render(input, opts); err != nil {
+			return nil, err
+		}
+	}
+	return output, nil
+}
+
+// outputPath derives the path to write a batch-mode result to from an
+// input path, cfg.OutDir and cfg.Ext.
+func outputPath(inputPath string) string {
+	base := filepath.Base(inputPath)
+	base = strings.TrimSuffix(base, filepath.Ext(base)) + cfg.Ext
+	return filepath.Join(cfg.OutDir, base)
+}
+
+// renderBatch renders each of paths concurrently using a worker pool sized
+// to GOMAXPROCS, writing each result alongside the others under cfg.OutDir.
+// It returns the first error encountered, if any, but always lets every
+// worker finish.
+func renderBatch(paths []string, b backend.Backend, opts backend.Options, explicitFlags map[string]bool) error {
+	jobs := make(chan string)
+	errs := make(chan error, len(paths))
+
+	var wg sync.WaitGroup
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				errs <- renderOne(path, b, opts, explicitFlags)
+			}
+		}()
+	}
+	for _, path := range paths {
+		jobs <- path
+	}
+	close(jobs)
+	wg.Wait()
+	close(errs)
+
+	var firstErr error
+	for err := range errs {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// renderOne reads, renders and writes a single file as part of batch mode.
+func renderOne(inputPath string, b backend.Backend, opts backend.Options, explicitFlags map[string]bool) error {
+	input, err := ioutil.ReadFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("reading %s: %v", inputPath, err)
+	}
+	// Use filename as title if there isn't one already
+	if opts.Title == "" {
+		opts.Title = inputPath
+	}
+	var frontVars map[string]interface{}
+	if input, frontVars, err = splitFrontMatter(input); err != nil {
+		return fmt.Errorf("%s: %v", inputPath, err)
+	}
+	opts, extraVars := applyFrontMatter(opts, frontVars, explicitFlags)
+	output, err := render(input, b, opts)
+	if err != nil {
+		return fmt.Errorf("rendering %s: %v", inputPath, err)
+	}
+	if cfg.Template != "" {
+		if output, err = wrapTemplate(output, inputPath, opts.Title, extraVars); err != nil {
+			return fmt.Errorf("applying template to %s: %v", inputPath, err)
+		}
+	}
+	out := outputPath(inputPath)
+	if err := ioutil.WriteFile(out, output, 0644); err != nil {
+		return fmt.Errorf("writing %s: %v", out, err)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestOutputPath(t *testing.T) {
+	origOutDir, origExt := cfg.OutDir, cfg.Ext
+	defer func() { cfg.OutDir, cfg.Ext = origOutDir, origExt }()
+
+	cases := []struct {
+		outDir, ext, inputPath, want string
+	}{
+		{".", ".html", "doc.md", "doc.html"},
+		{"out", ".html", "doc.md", "out/doc.html"},
+		{"out", ".htm", "sub/dir/doc.markdown", "out/doc.htm"},
+		{".", ".html", "doc", "doc.html"},
+	}
+	for _, c := range cases {
+		cfg.OutDir, cfg.Ext = c.outDir, c.ext
+		if got := outputPath(c.inputPath); got != c.want {
+			t.Errorf("outputPath(%q) with outdir=%q ext=%q = %q, want %q",
+				c.inputPath, c.outDir, c.ext, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+	"github.com/russross/blackfriday"
+	"github.com/scott-linder/blackfriday-tool/internal/backend"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// splitFrontMatter detects a leading "---" (YAML) or "+++" (TOML) front
+// matter block, decodes it, and returns the remaining markdown body
+// alongside the decoded keys. Input with no recognized front matter is
+// returned unchanged with a nil vars map.
+func splitFrontMatter(input []byte) (body []byte, vars map[string]interface{}, err error) {
+	var delim string
+	switch {
+	case bytes.HasPrefix(input, []byte("---\n")):
+		delim = "---"
+	case bytes.HasPrefix(input, []byte("+++\n")):
+		delim = "+++"
+	default:
+		return input, nil, nil
+	}
+
+	rest := input[len(delim)+1:]
+	closer := []byte("\n" + delim)
+	idx := bytes.Index(rest, closer)
+	if idx == -1 {
+		// no closing delimiter; treat the whole input as plain markdown
+		return input, nil, nil
+	}
+	raw := rest[:idx]
+	body = bytes.TrimPrefix(rest[idx+len(closer):], []byte("\n"))
+
+	vars = map[string]interface{}{}
+	switch delim {
+	case "---":
+		err = yaml.Unmarshal(raw, &vars)
+	case "+++":
+		_, err = toml.Decode(string(raw), &vars)
+	}
+	if err != nil {
+		return input, nil, fmt.Errorf("parsing front matter: %v", err)
+	}
+	return body, vars, nil
+}
+
+// applyFrontMatter overlays front-matter vars onto opts. Flags that were
+// explicitly set on the command line (per explicitFlags) take precedence
+// over the document's own front matter. Keys other than title, css, toc,
+// footnotes, section, date and source are returned, formatted as strings
+// with %v, for substitution by the template wrapper.
+//
+// blackfriday's HTML renderer only consults Title/CSS when
+// HTML_COMPLETE_PAGE is set, which cfg's own implied-option pass (run
+// before front matter is ever parsed) has no way to know about. So a
+// title/css supplied purely via front matter would otherwise render but
+// never actually appear; force the flag on here unless -template is
+// doing its own page framing instead.
+func applyFrontMatter(opts backend.Options, vars map[string]interface{}, explicitFlags map[string]bool) (backend.Options, map[string]string) {
+	extra := map[string]string{}
+	for key, val := range vars {
+		switch key {
+		case "title":
+			if !explicitFlags["title"] {
+				if s, ok := val.(string); ok {
+					opts.Title = s
+					if cfg.Template == "" {
+						opts.HTMLFlags |= blackfriday.HTML_COMPLETE_PAGE
+					}
+				}
+			}
+		case "css":
+			if !explicitFlags["css"] {
+				if s, ok := val.(string); ok {
+					opts.CSS = s
+					if cfg.Template == "" {
+						opts.HTMLFlags |= blackfriday.HTML_COMPLETE_PAGE
+					}
+				}
+			}
+		case "toc":
+			if !explicitFlags["toc"] {
+				setBit(&opts.HTMLFlags, blackfriday.HTML_TOC, val)
+			}
+		case "footnotes":
+			if !explicitFlags["footnotes"] {
+				setBit(&opts.Extensions, blackfriday.EXTENSION_FOOTNOTES, val)
+			}
+		case "section":
+			if !explicitFlags["man-section"] {
+				if val != nil {
+					opts.ManSection = fmt.Sprintf("%v", val)
+				}
+			}
+		case "date":
+			if !explicitFlags["man-date"] {
+				if s, ok := val.(string); ok {
+					opts.ManDate = s
+				}
+			}
+		case "source":
+			if !explicitFlags["man-source"] {
+				if s, ok := val.(string); ok {
+					opts.ManSource = s
+				}
+			}
+		default:
+			extra[key] = fmt.Sprintf("%v", val)
+		}
+	}
+	return opts, extra
+}
+
+// setBit sets or clears flag in *bits according to val, if val is a bool.
+// It reports whether val was a bool and the bit was adjusted.
+func setBit(bits *int, flag int, val interface{}) bool {
+	b, ok := val.(bool)
+	if !ok {
+		return false
+	}
+	if b {
+		*bits |= flag
+	} else {
+		*bits &^= flag
+	}
+	return true
+}
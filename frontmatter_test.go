@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/russross/blackfriday"
+	"github.com/scott-linder/blackfriday-tool/internal/backend"
+)
+
+func TestSplitFrontMatterYAML(t *testing.T) {
+	input := []byte("---\ntitle: Hi\ncss: style.css\n---\n# Body\n")
+	body, vars, err := splitFrontMatter(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "# Body\n" {
+		t.Errorf("body = %q, want %q", body, "# Body\n")
+	}
+	if vars["title"] != "Hi" || vars["css"] != "style.css" {
+		t.Errorf("vars = %#v", vars)
+	}
+}
+
+func TestSplitFrontMatterTOML(t *testing.T) {
+	input := []byte("+++\ntitle = \"Hi\"\n+++\n# Body\n")
+	body, vars, err := splitFrontMatter(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "# Body\n" {
+		t.Errorf("body = %q, want %q", body, "# Body\n")
+	}
+	if vars["title"] != "Hi" {
+		t.Errorf("vars = %#v", vars)
+	}
+}
+
+func TestSplitFrontMatterNone(t *testing.T) {
+	input := []byte("# Body\n")
+	body, vars, err := splitFrontMatter(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(body, input) {
+		t.Errorf("body = %q, want input unchanged", body)
+	}
+	if vars != nil {
+		t.Errorf("vars = %#v, want nil", vars)
+	}
+}
+
+func TestSplitFrontMatterUnclosed(t *testing.T) {
+	input := []byte("---\ntitle: Hi\n# Body\n")
+	body, vars, err := splitFrontMatter(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(body, input) {
+		t.Errorf("body = %q, want input unchanged", body)
+	}
+	if vars != nil {
+		t.Errorf("vars = %#v, want nil", vars)
+	}
+}
+
+func TestApplyFrontMatterSetsCompletePage(t *testing.T) {
+	origTemplate := cfg.Template
+	cfg.Template = ""
+	defer func() { cfg.Template = origTemplate }()
+
+	opts := backend.Options{}
+	vars := map[string]interface{}{"title": "Hi", "css": "style.css"}
+	opts, extra := applyFrontMatter(opts, vars, map[string]bool{})
+
+	if opts.Title != "Hi" || opts.CSS != "style.css" {
+		t.Errorf("opts = %#v", opts)
+	}
+	if opts.HTMLFlags&blackfriday.HTML_COMPLETE_PAGE == 0 {
+		t.Error("expected HTML_COMPLETE_PAGE to be set when front matter supplies title/css")
+	}
+	if len(extra) != 0 {
+		t.Errorf("extra = %#v, want empty", extra)
+	}
+}
+
+func TestApplyFrontMatterSkipsCompletePageWithTemplate(t *testing.T) {
+	origTemplate := cfg.Template
+	cfg.Template = "some.html"
+	defer func() { cfg.Template = origTemplate }()
+
+	opts := backend.Options{}
+	vars := map[string]interface{}{"title": "Hi"}
+	opts, _ = applyFrontMatter(opts, vars, map[string]bool{})
+
+	if opts.HTMLFlags&blackfriday.HTML_COMPLETE_PAGE != 0 {
+		t.Error("expected HTML_COMPLETE_PAGE to stay unset when -template is active")
+	}
+}
+
+func TestApplyFrontMatterExplicitFlagWins(t *testing.T) {
+	opts := backend.Options{Title: "From Flag"}
+	vars := map[string]interface{}{"title": "From Front Matter"}
+	opts, _ = applyFrontMatter(opts, vars, map[string]bool{"title": true})
+
+	if opts.Title != "From Flag" {
+		t.Errorf("opts.Title = %q, want %q", opts.Title, "From Flag")
+	}
+}
+
+func TestApplyFrontMatterExtraKeys(t *testing.T) {
+	opts := backend.Options{}
+	vars := map[string]interface{}{"author": "jane", "draft": true}
+	_, extra := applyFrontMatter(opts, vars, map[string]bool{})
+
+	if extra["author"] != "jane" || extra["draft"] != "true" {
+		t.Errorf("extra = %#v", extra)
+	}
+}
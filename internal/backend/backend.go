@@ -0,0 +1,58 @@
+// Package backend defines the interface implemented by each pluggable
+// markdown renderer selectable via the -renderer flag. Each registered
+// backend owns its whole parse-and-render pipeline rather than plugging
+// into a shared parser, so -renderer picks an output format (HTML, LaTeX,
+// groff, PDF) as much as a markdown engine; backends are not independently
+// combinable with one another.
+package backend
+
+import "fmt"
+
+// Options carries the subset of cfg that backends need in order to render.
+// Not every backend consults every field.
+type Options struct {
+	Extensions int
+	HTMLFlags  int
+	Title      string
+	CSS        string
+	PDFLatex   string
+	ManSection string
+	ManDate    string
+	ManSource  string
+}
+
+// Backend renders markdown input into the backend's output format.
+type Backend interface {
+	Render(input []byte, opts Options) ([]byte, error)
+}
+
+type registration struct {
+	factory func() Backend
+	html    bool
+}
+
+var registry = map[string]registration{}
+
+// Register makes a backend available under name. It is meant to be called
+// from the init function of a backend implementation package. html
+// reports whether the backend's output is HTML suitable for wrapping
+// with -template.
+func Register(name string, factory func() Backend, html bool) {
+	registry[name] = registration{factory: factory, html: html}
+}
+
+// New looks up the backend registered under name.
+func New(name string) (Backend, error) {
+	r, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown renderer backend: %q", name)
+	}
+	return r.factory(), nil
+}
+
+// ProducesHTML reports whether the named backend's output is HTML, and so
+// can be safely wrapped with -template. It returns false for an unknown
+// name; callers that care should check New's error first.
+func ProducesHTML(name string) bool {
+	return registry[name].html
+}
@@ -0,0 +1,27 @@
+// Package blackfriday implements the default backend.Backend, rendering
+// HTML via the russross/blackfriday parser.
+package blackfriday
+
+import (
+	"github.com/russross/blackfriday"
+	"github.com/scott-linder/blackfriday-tool/internal/backend"
+)
+
+func init() {
+	backend.Register("blackfriday", func() backend.Backend { return New() }, true)
+}
+
+// Renderer renders markdown to HTML using blackfriday's own renderer and
+// extension set, matching the tool's historical default behavior.
+type Renderer struct{}
+
+// New returns a Renderer.
+func New() *Renderer {
+	return &Renderer{}
+}
+
+// Render implements backend.Backend.
+func (r *Renderer) Render(input []byte, opts backend.Options) ([]byte, error) {
+	htmlRenderer := blackfriday.HtmlRenderer(opts.HTMLFlags, opts.Title, opts.CSS)
+	return blackfriday.Markdown(input, htmlRenderer, opts.Extensions), nil
+}
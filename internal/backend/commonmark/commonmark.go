@@ -0,0 +1,34 @@
+// Package commonmark implements the backend.Backend that renders strict
+// CommonMark, with no GFM or blackfriday extensions enabled, for callers
+// who need spec-conformant output rather than this tool's usual dialect.
+package commonmark
+
+import (
+	"bytes"
+
+	"github.com/scott-linder/blackfriday-tool/internal/backend"
+	"github.com/yuin/goldmark"
+)
+
+func init() {
+	backend.Register("commonmark", func() backend.Backend { return New() }, true)
+}
+
+// Renderer renders plain CommonMark to HTML.
+type Renderer struct {
+	md goldmark.Markdown
+}
+
+// New returns a Renderer.
+func New() *Renderer {
+	return &Renderer{md: goldmark.New()}
+}
+
+// Render implements backend.Backend.
+func (r *Renderer) Render(input []byte, opts backend.Options) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := r.md.Convert(input, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,39 @@
+// Package goldmark implements the backend.Backend that renders markdown
+// with the goldmark library and its GitHub Flavored Markdown extensions,
+// for callers who want CommonMark-compatible output with tables,
+// strikethrough and autolinks rather than blackfriday's own dialect.
+package goldmark
+
+import (
+	"bytes"
+
+	"github.com/scott-linder/blackfriday-tool/internal/backend"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+func init() {
+	backend.Register("goldmark", func() backend.Backend { return New() }, true)
+}
+
+// Renderer renders markdown to HTML using goldmark with GFM extensions
+// enabled.
+type Renderer struct {
+	md goldmark.Markdown
+}
+
+// New returns a Renderer.
+func New() *Renderer {
+	return &Renderer{
+		md: goldmark.New(goldmark.WithExtensions(extension.GFM)),
+	}
+}
+
+// Render implements backend.Backend.
+func (r *Renderer) Render(input []byte, opts backend.Options) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := r.md.Convert(input, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
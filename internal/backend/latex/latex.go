@@ -0,0 +1,26 @@
+// Package latex implements the backend.Backend that renders markdown to
+// LaTeX via blackfriday's LatexRenderer.
+package latex
+
+import (
+	"github.com/russross/blackfriday"
+	"github.com/scott-linder/blackfriday-tool/internal/backend"
+)
+
+func init() {
+	backend.Register("latex", func() backend.Backend { return New() }, false)
+}
+
+// Renderer renders markdown to LaTeX.
+type Renderer struct{}
+
+// New returns a Renderer.
+func New() *Renderer {
+	return &Renderer{}
+}
+
+// Render implements backend.Backend.
+func (r *Renderer) Render(input []byte, opts backend.Options) ([]byte, error) {
+	latexRenderer := blackfriday.LatexRenderer(0)
+	return blackfriday.Markdown(input, latexRenderer, opts.Extensions), nil
+}
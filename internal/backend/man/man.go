@@ -0,0 +1,269 @@
+// Package man implements the backend.Backend that renders markdown to
+// groff, suitable for piping into nroff/man.
+package man
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/russross/blackfriday"
+	"github.com/scott-linder/blackfriday-tool/internal/backend"
+)
+
+func init() {
+	backend.Register("man", func() backend.Backend { return New() }, false)
+}
+
+// Renderer renders markdown to man's input using blackfriday's own
+// parser, driven by a ManRenderer implementing blackfriday.Renderer.
+type Renderer struct{}
+
+// New returns a Renderer.
+func New() *Renderer {
+	return &Renderer{}
+}
+
+// Render implements backend.Backend.
+func (r *Renderer) Render(input []byte, opts backend.Options) ([]byte, error) {
+	manRenderer := &ManRenderer{
+		title:   opts.Title,
+		section: opts.ManSection,
+		date:    opts.ManDate,
+		source:  opts.ManSource,
+	}
+	return blackfriday.Markdown(input, manRenderer, opts.Extensions), nil
+}
+
+// ManRenderer implements blackfriday.Renderer, emitting groff suitable for
+// man(7): .TH for the page header, .SH/.SS for headings, .PP for
+// paragraphs, .IP for list items, and \fB/\fI/\fR for emphasis.
+type ManRenderer struct {
+	title, section, date, source string
+
+	// orderedStack holds the running item number for each ordered list
+	// currently open, innermost last, so nested ordered lists each count
+	// from 1 independently.
+	orderedStack []int
+}
+
+// BlockCode renders a fenced or indented code block as a literal,
+// monospaced display.
+func (r *ManRenderer) BlockCode(out *bytes.Buffer, text []byte, lang string) {
+	out.WriteString(".PP\n.RS\n.nf\n")
+	escapeText(out, text)
+	out.WriteString("\n.fi\n.RE\n")
+}
+
+// BlockQuote renders a block quote as an indented region.
+func (r *ManRenderer) BlockQuote(out *bytes.Buffer, text []byte) {
+	out.WriteString(".RS\n")
+	out.Write(text)
+	out.WriteString(".RE\n")
+}
+
+// BlockHtml is not representable in groff, so raw HTML is dropped.
+func (r *ManRenderer) BlockHtml(out *bytes.Buffer, text []byte) {}
+
+// Header renders a heading: level 1 as .SH, deeper levels as .SS.
+func (r *ManRenderer) Header(out *bytes.Buffer, text func() bool, level int, id string) {
+	macro := ".SS"
+	if level == 1 {
+		macro = ".SH"
+	}
+	out.WriteString(macro + " \"")
+	text()
+	out.WriteString("\"\n")
+}
+
+// HRule renders a horizontal rule as a blank-line break; groff has no
+// direct equivalent worth emitting.
+func (r *ManRenderer) HRule(out *bytes.Buffer) {
+	out.WriteString(".PP\n")
+}
+
+// List renders an ordered or unordered list, pushing a fresh item counter
+// for LIST_TYPE_ORDERED so nested ListItem calls can number themselves.
+func (r *ManRenderer) List(out *bytes.Buffer, text func() bool, flags int) {
+	if flags&blackfriday.LIST_TYPE_ORDERED != 0 {
+		r.orderedStack = append(r.orderedStack, 0)
+		defer func() { r.orderedStack = r.orderedStack[:len(r.orderedStack)-1] }()
+	}
+	text()
+}
+
+// ListItem renders a single list item as a tagged paragraph: "N." for the
+// innermost open ordered list, or a bullet otherwise.
+func (r *ManRenderer) ListItem(out *bytes.Buffer, text []byte, flags int) {
+	if flags&blackfriday.LIST_TYPE_ORDERED != 0 && len(r.orderedStack) > 0 {
+		top := len(r.orderedStack) - 1
+		r.orderedStack[top]++
+		fmt.Fprintf(out, ".IP \"%d.\" 4\n", r.orderedStack[top])
+	} else {
+		out.WriteString(".IP \\(bu 2\n")
+	}
+	out.Write(text)
+	out.WriteString("\n")
+}
+
+// Paragraph renders a paragraph, starting a new one with .PP.
+func (r *ManRenderer) Paragraph(out *bytes.Buffer, text func() bool) {
+	out.WriteString(".PP\n")
+	text()
+	out.WriteString("\n")
+}
+
+// Table is not rendered; groff tables require tbl(1) preprocessing that is
+// out of scope here.
+func (r *ManRenderer) Table(out *bytes.Buffer, header []byte, body []byte, columnData []int) {
+}
+
+// TableRow is a no-op; see Table.
+func (r *ManRenderer) TableRow(out *bytes.Buffer, text []byte) {}
+
+// TableHeaderCell is a no-op; see Table.
+func (r *ManRenderer) TableHeaderCell(out *bytes.Buffer, text []byte, flags int) {}
+
+// TableCell is a no-op; see Table.
+func (r *ManRenderer) TableCell(out *bytes.Buffer, text []byte, flags int) {}
+
+// Footnotes renders the footnote block.
+func (r *ManRenderer) Footnotes(out *bytes.Buffer, text func() bool) {
+	out.WriteString(".SH NOTES\n")
+	text()
+}
+
+// FootnoteItem renders a single footnote as a tagged paragraph.
+func (r *ManRenderer) FootnoteItem(out *bytes.Buffer, name []byte, text []byte, flags int) {
+	out.WriteString(".IP [" + string(name) + "]\n")
+	out.Write(text)
+	out.WriteString("\n")
+}
+
+// TitleBlock is unused; the page header is emitted from DocumentHeader.
+func (r *ManRenderer) TitleBlock(out *bytes.Buffer, text []byte) {}
+
+// AutoLink renders a bare URL verbatim.
+func (r *ManRenderer) AutoLink(out *bytes.Buffer, link []byte, kind int) {
+	out.Write(link)
+}
+
+// CodeSpan renders inline code in bold, the man(7) convention.
+func (r *ManRenderer) CodeSpan(out *bytes.Buffer, text []byte) {
+	out.WriteString("\\fB")
+	escapeText(out, text)
+	out.WriteString("\\fR")
+}
+
+// DoubleEmphasis renders strong emphasis in bold.
+func (r *ManRenderer) DoubleEmphasis(out *bytes.Buffer, text []byte) {
+	out.WriteString("\\fB")
+	out.Write(text)
+	out.WriteString("\\fR")
+}
+
+// Emphasis renders emphasis in italics.
+func (r *ManRenderer) Emphasis(out *bytes.Buffer, text []byte) {
+	out.WriteString("\\fI")
+	out.Write(text)
+	out.WriteString("\\fR")
+}
+
+// Image has no groff equivalent, so only its alt text is rendered.
+func (r *ManRenderer) Image(out *bytes.Buffer, link []byte, title []byte, alt []byte) {
+	out.Write(alt)
+}
+
+// LineBreak renders a forced line break.
+func (r *ManRenderer) LineBreak(out *bytes.Buffer) {
+	out.WriteString("\n.br\n")
+}
+
+// Link renders link text followed by the URL in parentheses, since groff
+// viewers have no notion of a hyperlink.
+func (r *ManRenderer) Link(out *bytes.Buffer, link []byte, title []byte, content []byte) {
+	out.Write(content)
+	out.WriteString(" (")
+	out.Write(link)
+	out.WriteString(")")
+}
+
+// RawHtmlTag is dropped; see BlockHtml.
+func (r *ManRenderer) RawHtmlTag(out *bytes.Buffer, tag []byte) {}
+
+// TripleEmphasis renders bold italics.
+func (r *ManRenderer) TripleEmphasis(out *bytes.Buffer, text []byte) {
+	out.WriteString("\\f(BI")
+	out.Write(text)
+	out.WriteString("\\fR")
+}
+
+// StrikeThrough has no groff equivalent, so the text is rendered plain.
+func (r *ManRenderer) StrikeThrough(out *bytes.Buffer, text []byte) {
+	out.Write(text)
+}
+
+// FootnoteRef renders a footnote reference as its bracketed label, matching
+// the label FootnoteItem prints.
+func (r *ManRenderer) FootnoteRef(out *bytes.Buffer, ref []byte, id int) {
+	out.WriteString("[")
+	out.Write(ref)
+	out.WriteString("]")
+}
+
+// Entity renders an HTML entity verbatim; groff tolerates the common ones.
+func (r *ManRenderer) Entity(out *bytes.Buffer, entity []byte) {
+	out.Write(entity)
+}
+
+// NormalText escapes groff control characters before writing plain text.
+func (r *ManRenderer) NormalText(out *bytes.Buffer, text []byte) {
+	escapeText(out, text)
+}
+
+// DocumentHeader emits the .TH page header: title, section (from
+// -man-section or a "section" front-matter key, default "1"), and the
+// optional date and source (-man-date/-man-source, or "date"/"source"
+// front-matter keys).
+func (r *ManRenderer) DocumentHeader(out *bytes.Buffer) {
+	title := r.title
+	if title == "" {
+		title = "UNTITLED"
+	}
+	section := r.section
+	if section == "" {
+		section = "1"
+	}
+	out.WriteString(".TH \"" + title + "\" \"" + section + "\"")
+	if r.date != "" || r.source != "" {
+		out.WriteString(" \"" + r.date + "\"")
+	}
+	if r.source != "" {
+		out.WriteString(" \"" + r.source + "\"")
+	}
+	out.WriteString("\n")
+}
+
+// DocumentFooter is a no-op; man pages need no trailing macro.
+func (r *ManRenderer) DocumentFooter(out *bytes.Buffer) {}
+
+// GetFlags returns no renderer-specific flags; the man backend does not
+// consult blackfriday's HTML_* flag set.
+func (r *ManRenderer) GetFlags() int {
+	return 0
+}
+
+// escapeText escapes groff's backslash control character, and guards a
+// leading dot or tick so that text can't be misread as a macro request
+// when it lands at the start of a line.
+func escapeText(out *bytes.Buffer, text []byte) {
+	text = bytes.Replace(text, []byte(`\`), []byte(`\\`), -1)
+	for i, line := range bytes.Split(text, []byte("\n")) {
+		if i > 0 {
+			out.WriteByte('\n')
+		}
+		if len(line) > 0 && (line[0] == '.' || line[0] == '\'') {
+			out.WriteString("\\&")
+		}
+		out.Write(line)
+	}
+}
@@ -0,0 +1,24 @@
+package man
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEscapeText(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"plain text", "plain text"},
+		{".PP looks like a macro", "\\&.PP looks like a macro"},
+		{"'quoted macro", "\\&'quoted macro"},
+		{`back\slash`, `back\\slash`},
+	}
+	for _, c := range cases {
+		var buf bytes.Buffer
+		escapeText(&buf, []byte(c.in))
+		if got := buf.String(); got != c.want {
+			t.Errorf("escapeText(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
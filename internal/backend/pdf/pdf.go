@@ -0,0 +1,58 @@
+// Package pdf implements the backend.Backend that renders markdown to PDF
+// by rendering to LaTeX and shelling out to a pdflatex binary.
+package pdf
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/russross/blackfriday"
+	"github.com/scott-linder/blackfriday-tool/internal/backend"
+)
+
+func init() {
+	backend.Register("pdf", func() backend.Backend { return New() }, false)
+}
+
+// Renderer renders markdown to PDF via an intermediate LaTeX document
+// compiled in a scratch directory.
+type Renderer struct{}
+
+// New returns a Renderer.
+func New() *Renderer {
+	return &Renderer{}
+}
+
+// Render implements backend.Backend. It renders input to LaTeX, writes it
+// to a temp directory, runs opts.PDFLatex over it, and returns the
+// resulting PDF bytes.
+func (r *Renderer) Render(input []byte, opts backend.Options) ([]byte, error) {
+	latexRenderer := blackfriday.LatexRenderer(0)
+	tex := blackfriday.Markdown(input, latexRenderer, opts.Extensions)
+
+	dir, err := ioutil.TempDir("", "blackfriday-pdf")
+	if err != nil {
+		return nil, fmt.Errorf("creating scratch directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	texPath := filepath.Join(dir, "doc.tex")
+	if err := ioutil.WriteFile(texPath, tex, 0644); err != nil {
+		return nil, fmt.Errorf("writing %s: %v", texPath, err)
+	}
+
+	pdflatex := opts.PDFLatex
+	if pdflatex == "" {
+		pdflatex = "pdflatex"
+	}
+	cmd := exec.Command(pdflatex,
+		"-interaction=nonstopmode", "-output-directory", dir, texPath)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%s: %v\n%s", pdflatex, err, out)
+	}
+
+	return ioutil.ReadFile(filepath.Join(dir, "doc.pdf"))
+}
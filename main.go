@@ -21,6 +21,13 @@ import (
 	"flag"
 	"fmt"
 	"github.com/russross/blackfriday"
+	"github.com/scott-linder/blackfriday-tool/internal/backend"
+	_ "github.com/scott-linder/blackfriday-tool/internal/backend/blackfriday"
+	_ "github.com/scott-linder/blackfriday-tool/internal/backend/commonmark"
+	_ "github.com/scott-linder/blackfriday-tool/internal/backend/goldmark"
+	_ "github.com/scott-linder/blackfriday-tool/internal/backend/latex"
+	_ "github.com/scott-linder/blackfriday-tool/internal/backend/man"
+	_ "github.com/scott-linder/blackfriday-tool/internal/backend/pdf"
 	"io"
 	"io/ioutil"
 	"log"
@@ -47,12 +54,27 @@ var cfg = struct {
 	CSS         string
 	CPUProfile  string
 	Repeat      int
+	Renderer    string
+	OutDir      string
+	Ext         string
+	Template    string
+	PDFLatex    string
+	Watch       bool
+	InDir       string
+	ManSection  string
+	ManDate     string
+	ManSource   string
 }{
 	XHTML:       true,
 	Smartypants: true,
 	LatexDashes: true,
 	Fractions:   true,
 	Repeat:      1,
+	Renderer:    "blackfriday",
+	OutDir:      ".",
+	Ext:         ".html",
+	PDFLatex:    "pdflatex",
+	ManSection:  "1",
 }
 
 // Parse config file; error will never be never be NotExist
@@ -108,6 +130,38 @@ func parseFlags() {
 		"Write cpu profile to a file")
 	flag.IntVar(&cfg.Repeat, "repeat", cfg.Repeat,
 		"Process the input multiple times (for benchmarking)")
+	flag.StringVar(&cfg.Renderer, "renderer", cfg.Renderer,
+		"Output backend to use: goldmark, commonmark and blackfriday "+
+			"produce HTML, latex produces LaTeX, man produces groff, and "+
+			"pdf compiles the latex path with -pdflatex. Each backend owns "+
+			"its whole parse-and-render pipeline, so this selects an "+
+			"output format as much as a markdown engine; there is no way "+
+			"to mix, e.g., the goldmark parser with the pdf backend's "+
+			"LaTeX path")
+	flag.StringVar(&cfg.PDFLatex, "pdflatex", cfg.PDFLatex,
+		"pdflatex command to invoke for -renderer=pdf")
+	flag.StringVar(&cfg.ManSection, "man-section", cfg.ManSection,
+		"Man page section for -renderer=man's .TH header")
+	flag.StringVar(&cfg.ManDate, "man-date", cfg.ManDate,
+		"Man page date for -renderer=man's .TH header")
+	flag.StringVar(&cfg.ManSource, "man-source", cfg.ManSource,
+		"Man page source for -renderer=man's .TH header")
+	flag.BoolVar(&cfg.Watch, "watch", cfg.Watch,
+		"Watch input file(s) or -indir and re-render on change "+
+			"(implies -cpuprofile is ignored); positional args are always "+
+			"inputs, so the legacy [inputfile outputfile] pair is rejected")
+	flag.StringVar(&cfg.InDir, "indir", cfg.InDir,
+		"Directory of input files for -watch to render and observe "+
+			"(ignored without -watch)")
+	flag.StringVar(&cfg.OutDir, "outdir", cfg.OutDir,
+		"Directory to write outputs to when more than one input file is "+
+			"given, or with -watch")
+	flag.StringVar(&cfg.Ext, "ext", cfg.Ext,
+		"Output file extension to use when more than one input file is "+
+			"given, or with -watch")
+	flag.StringVar(&cfg.Template, "template", cfg.Template,
+		"HTML template file with {{title}}, {{filename}} and {{content}} "+
+			"placeholders to wrap output in (implies -page=false)")
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Blackfriday Markdown Processor v"+
 			blackfriday.VERSION+
@@ -116,7 +170,8 @@ func parseFlags() {
 			"Distributed under the Simplified BSD License\n"+
 			"See website for details\n\n"+
 			"Usage:\n"+
-			"  %s [options] [inputfile [outputfile]]\n\n"+
+			"  %s [options] [inputfile [outputfile]]\n"+
+			"  %[1]s [options] inputfile inputfile inputfile...\n\n"+
 			"Options:\n",
 			os.Args[0])
 		flag.PrintDefaults()
@@ -130,6 +185,8 @@ func main() {
 		log.Printf("warn: problem parsing config: %v", err)
 	}
 	parseFlags()
+	explicitFlags := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { explicitFlags[f.Name] = true })
 
 	// enforce implied options
 	if cfg.CSS != "" || cfg.Title != "" {
@@ -144,9 +201,18 @@ func main() {
 	if cfg.TOC {
 		cfg.Latex = false
 	}
+	// -latex is kept as a shorthand for -renderer=latex
+	if cfg.Latex {
+		cfg.Renderer = "latex"
+	}
+	// -template takes over page framing from HTML_COMPLETE_PAGE
+	if cfg.Template != "" {
+		cfg.Page = false
+	}
 
-	// turn on profiling?
-	if cfg.CPUProfile != "" {
+	// turn on profiling? (skipped under -watch, which would otherwise
+	// corrupt the profile across rebuilds)
+	if cfg.CPUProfile != "" && !cfg.Watch {
 		f, err := os.Create(cfg.CPUProfile)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
@@ -159,24 +225,29 @@ func main() {
 	var input []byte
 	var err error
 	args := flag.Args()
-	switch len(args) {
-	case 0:
-		if input, err = ioutil.ReadAll(os.Stdin); err != nil {
-			fmt.Fprintln(os.Stderr, "Error reading from Stdin:", err)
-			os.Exit(-1)
+	batch := len(args) > 2
+	var frontVars map[string]interface{}
+	if !batch && !cfg.Watch {
+		switch len(args) {
+		case 0:
+			if input, err = ioutil.ReadAll(os.Stdin); err != nil {
+				fmt.Fprintln(os.Stderr, "Error reading from Stdin:", err)
+				os.Exit(-1)
+			}
+		case 1, 2:
+			if input, err = ioutil.ReadFile(args[0]); err != nil {
+				fmt.Fprintln(os.Stderr, "Error reading from", args[0], ":", err)
+				os.Exit(-1)
+			}
+			// Use filename as title if there isn't one already
+			if cfg.Title == "" {
+				cfg.Title = args[0]
+			}
 		}
-	case 1, 2:
-		if input, err = ioutil.ReadFile(args[0]); err != nil {
-			fmt.Fprintln(os.Stderr, "Error reading from", args[0], ":", err)
+		if input, frontVars, err = splitFrontMatter(input); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
 			os.Exit(-1)
 		}
-		// Use filename as title if there isn't one already
-		if cfg.Title == "" {
-			cfg.Title = args[0]
-		}
-	default:
-		flag.Usage()
-		os.Exit(-1)
 	}
 
 	// set up options
@@ -191,41 +262,107 @@ func main() {
 		extensions |= blackfriday.EXTENSION_FOOTNOTES
 	}
 
-	var renderer blackfriday.Renderer
-	if cfg.Latex {
-		// render the data into LaTeX
-		renderer = blackfriday.LatexRenderer(0)
-	} else {
-		// render the data into HTML
-		htmlFlags := 0
-		if cfg.XHTML {
-			htmlFlags |= blackfriday.HTML_USE_XHTML
-		}
-		if cfg.Smartypants {
-			htmlFlags |= blackfriday.HTML_USE_SMARTYPANTS
-		}
-		if cfg.Fractions {
-			htmlFlags |= blackfriday.HTML_SMARTYPANTS_FRACTIONS
+	htmlFlags := 0
+	if cfg.XHTML {
+		htmlFlags |= blackfriday.HTML_USE_XHTML
+	}
+	if cfg.Smartypants {
+		htmlFlags |= blackfriday.HTML_USE_SMARTYPANTS
+	}
+	if cfg.Fractions {
+		htmlFlags |= blackfriday.HTML_SMARTYPANTS_FRACTIONS
+	}
+	if cfg.LatexDashes {
+		htmlFlags |= blackfriday.HTML_SMARTYPANTS_LATEX_DASHES
+	}
+	if cfg.Page {
+		htmlFlags |= blackfriday.HTML_COMPLETE_PAGE
+	}
+	if cfg.TOCOnly {
+		htmlFlags |= blackfriday.HTML_OMIT_CONTENTS
+	}
+	if cfg.TOC {
+		htmlFlags |= blackfriday.HTML_TOC
+	}
+
+	b, err := backend.New(cfg.Renderer)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(-1)
+	}
+	if cfg.Template != "" && !backend.ProducesHTML(cfg.Renderer) {
+		fmt.Fprintf(os.Stderr,
+			"Error: -template requires an HTML-producing -renderer, not %q\n",
+			cfg.Renderer)
+		os.Exit(-1)
+	}
+	opts := backend.Options{
+		Extensions: extensions,
+		HTMLFlags:  htmlFlags,
+		Title:      cfg.Title,
+		CSS:        cfg.CSS,
+		PDFLatex:   cfg.PDFLatex,
+		ManSection: cfg.ManSection,
+		ManDate:    cfg.ManDate,
+		ManSource:  cfg.ManSource,
+	}
+
+	// -watch takes over entirely: it renders once up front, then blocks,
+	// re-rendering only the file that changed on each subsequent write
+	if cfg.Watch {
+		if len(args) == 2 {
+			fmt.Fprintln(os.Stderr, "Error: -watch takes input file(s) only, not the "+
+				"legacy [inputfile outputfile] pair; use -outdir/-ext instead")
+			os.Exit(-1)
 		}
-		if cfg.LatexDashes {
-			htmlFlags |= blackfriday.HTML_SMARTYPANTS_LATEX_DASHES
+		watchPaths := args
+		if cfg.InDir != "" {
+			dirFiles, err := filesInDir(cfg.InDir)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				os.Exit(-1)
+			}
+			watchPaths = append(watchPaths, dirFiles...)
 		}
-		if cfg.Page {
-			htmlFlags |= blackfriday.HTML_COMPLETE_PAGE
+		if len(watchPaths) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: -watch requires input file(s) or -indir")
+			os.Exit(-1)
 		}
-		if cfg.TOCOnly {
-			htmlFlags |= blackfriday.HTML_OMIT_CONTENTS
+		if err := runWatch(watchPaths, b, opts, explicitFlags); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(-1)
 		}
-		if cfg.TOC {
-			htmlFlags |= blackfriday.HTML_TOC
+		return
+	}
+
+	// with more than one input file, render each concurrently into cfg.OutDir
+	// instead of the usual single-document stdin/stdout pipeline
+	if batch {
+		if err = renderBatch(args, b, opts, explicitFlags); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(-1)
 		}
-		renderer = blackfriday.HtmlRenderer(htmlFlags, cfg.Title, cfg.CSS)
+		return
 	}
 
+	opts, extraVars := applyFrontMatter(opts, frontVars, explicitFlags)
+
 	// parse and render
 	var output []byte
-	for i := 0; i < cfg.Repeat; i++ {
-		output = blackfriday.Markdown(input, renderer, extensions)
+	if output, err = render(input, b, opts); err != nil {
+		fmt.Fprintln(os.Stderr, "Error rendering:", err)
+		os.Exit(-1)
+	}
+
+	if cfg.Template != "" {
+		var filename string
+		if len(args) > 0 {
+			filename = args[0]
+		}
+		if output, err = wrapTemplate(output, filename, opts.Title, extraVars); err != nil {
+			fmt.Fprintln(os.Stderr, "Error applying template:", err)
+			os.Exit(-1)
+		}
 	}
 
 	// output the result
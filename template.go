@@ -0,0 +1,36 @@
+package main
+
+import (
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+// h1Pattern matches the first H1 in rendered HTML output, used to infer a
+// page title when none was given explicitly.
+var h1Pattern = regexp.MustCompile(`(?is)<h1[^>]*>(.*?)</h1>`)
+
+// wrapTemplate substitutes filename, title, the rendered body and any
+// extra front-matter keys into the HTML shell loaded from cfg.Template,
+// replacing {{filename}}, {{title}}, {{content}} and {{key}} for each
+// entry in extra respectively. When title is empty it is taken from the
+// document's first H1, if any.
+func wrapTemplate(body []byte, filename, title string, extra map[string]string) ([]byte, error) {
+	tmpl, err := ioutil.ReadFile(cfg.Template)
+	if err != nil {
+		return nil, err
+	}
+	if title == "" {
+		if m := h1Pattern.FindSubmatch(body); m != nil {
+			title = string(m[1])
+		}
+	}
+	page := string(tmpl)
+	page = strings.Replace(page, "{{title}}", title, -1)
+	page = strings.Replace(page, "{{filename}}", filename, -1)
+	page = strings.Replace(page, "{{content}}", string(body), -1)
+	for key, val := range extra {
+		page = strings.Replace(page, "{{"+key+"}}", val, -1)
+	}
+	return []byte(page), nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func withTemplate(t *testing.T, contents string) func() {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "blackfriday-template-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "tmpl.html")
+	if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	origTemplate := cfg.Template
+	cfg.Template = path
+	return func() {
+		cfg.Template = origTemplate
+		os.RemoveAll(dir)
+	}
+}
+
+func TestWrapTemplateExplicitTitle(t *testing.T) {
+	defer withTemplate(t, "<title>{{title}}</title><body>{{content}}</body>")()
+
+	out, err := wrapTemplate([]byte("<p>hi</p>"), "doc.md", "Explicit Title", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "<title>Explicit Title</title><body><p>hi</p></body>"
+	if string(out) != want {
+		t.Errorf("wrapTemplate() = %q, want %q", out, want)
+	}
+}
+
+func TestWrapTemplateTitleFromH1(t *testing.T) {
+	defer withTemplate(t, "<title>{{title}}</title>{{content}}")()
+
+	out, err := wrapTemplate([]byte("<h1>Inferred</h1><p>body</p>"), "doc.md", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "<title>Inferred</title><h1>Inferred</h1><p>body</p>"
+	if string(out) != want {
+		t.Errorf("wrapTemplate() = %q, want %q", out, want)
+	}
+}
+
+func TestWrapTemplateFilenameAndExtra(t *testing.T) {
+	defer withTemplate(t, "{{filename}}:{{author}}:{{content}}")()
+
+	out, err := wrapTemplate([]byte("body"), "doc.md", "t",
+		map[string]string{"author": "jane"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "doc.md:jane:body"
+	if string(out) != want {
+		t.Errorf("wrapTemplate() = %q, want %q", out, want)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/scott-linder/blackfriday-tool/internal/backend"
+)
+
+// filesInDir lists the regular files directly inside dir, for -watch
+// -indir's initial file set.
+func filesInDir(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	return paths, nil
+}
+
+// runWatch renders each of paths once up front, then uses fsnotify to
+// watch their containing directories for writes, re-rendering only the
+// file that changed. It blocks until the watcher's channels are closed.
+func runWatch(paths []string, b backend.Backend, opts backend.Options, explicitFlags map[string]bool) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("starting watcher: %v", err)
+	}
+	defer watcher.Close()
+
+	interesting := map[string]string{}
+	watchedDirs := map[string]bool{}
+	for _, path := range paths {
+		interesting[filepath.Clean(path)] = path
+		dir := filepath.Dir(path)
+		if !watchedDirs[dir] {
+			watchedDirs[dir] = true
+			if err := watcher.Add(dir); err != nil {
+				return fmt.Errorf("watching %s: %v", dir, err)
+			}
+		}
+		rebuild(path, b, opts, explicitFlags)
+	}
+
+	fmt.Fprintln(os.Stderr, "Watching for changes, press Ctrl-C to stop...")
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			path, ok := interesting[filepath.Clean(event.Name)]
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 || !ok {
+				continue
+			}
+			rebuild(path, b, opts, explicitFlags)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintln(os.Stderr, "Watch error:", err)
+		}
+	}
+}
+
+// rebuild re-renders a single file as part of watch mode, printing how
+// long the render took.
+func rebuild(path string, b backend.Backend, opts backend.Options, explicitFlags map[string]bool) {
+	start := time.Now()
+	if err := renderOne(path, b, opts, explicitFlags); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "rebuilt %s in %s\n", path, time.Since(start))
+}